@@ -1,8 +1,9 @@
 // Package quotable provides consistent splitting of strings into words,
 // allowing quoting so returned words can contain spaces. It also supports
 // backslashes, and optionally "fancy" backslashes, allowing C/Go-style
-// backslash escapes to be interpolated. It does not currently support
-// single-quotes, unlike Bourne shell.
+// backslash escapes to be interpolated. Single-quoted literal regions, as
+// in Bourne shell, are available via the SingleQuotes option. CommentChar
+// and HereDoc add shell/shlex-style comments and "<<TAG" here-docs.
 package quotable
 
 import (
@@ -16,9 +17,64 @@ import (
 // are framed such that "false" is the default behavior.
 type Options struct {
 	// Also support backslash escape sequences for unicode and special characters.
-	FancyBackslash   bool
+	FancyBackslash bool
 	// Only accept spaces, not arbitrary things for which unicode.IsSpace() returns true.
 	OnlySpaceIsSpace bool
+	// Treat '...' as a literal-string region, POSIX-shell style: no backslash
+	// escaping, no special-casing of ", and the only terminator is the
+	// matching '.
+	SingleQuotes bool
+	// Escapes overrides the escape table FancyBackslash consults; nil means
+	// DefaultEscapeTable. Only meaningful when FancyBackslash is set.
+	Escapes *EscapeTable
+	// StrictEscapes turns a short \x/\u/\U sequence (fewer hex digits than
+	// required) into an InvalidEscape error and drops its value, instead of
+	// the default behavior of silently zero-filling the missing digits. An
+	// unrecognized backslash escape is reported as InvalidEscape instead of
+	// the default error naming the character; in both modes the character
+	// itself is still written.
+	StrictEscapes bool
+	// VariableWidthHex lowers the digit count StrictEscapes requires for \x
+	// and \u (but not \U) to 1, so 1-2 and 1-4 hex digits respectively,
+	// terminated by the first non-hex-digit character, are accepted rather
+	// than rejected. Without StrictEscapes, short sequences are accepted
+	// either way, and this option changes nothing.
+	VariableWidthHex bool
+	// CommentChar, if non-zero, marks an unquoted, unescaped occurrence of
+	// that rune as the start of a comment running through (but not
+	// including) the next '\n', POSIX-shell/shlex style. A typical value
+	// is '#'.
+	CommentChar rune
+	// HereDoc recognizes "<<TAG\n...\nTAG\n", POSIX-shell style, as a
+	// single word whose value is the body between the two TAG lines,
+	// verbatim: no backslash or quote handling inside the body.
+	HereDoc bool
+}
+
+// EscapeTable controls which backslash escapes fancyBackslash recognizes.
+// Simple maps a single escape character straight to a replacement rune (the
+// default table has the usual C/Go set: \a \b \f \n \r \t \v \\ \" \').
+// Multi maps an escape character to the number of hex digits it introduces
+// (the default table has \x, \u, and \U, at 2/4/8 digits). Named enables
+// \N{UNICODE NAME} lookups against a small bundled name table. Octal enables
+// \0-\377 octal escapes.
+type EscapeTable struct {
+	Simple map[rune]rune
+	Multi  map[rune]int
+	Named  bool
+	Octal  bool
+}
+
+// DefaultEscapeTable is the table fancyBackslash uses when Options.Escapes
+// is nil.
+var DefaultEscapeTable = &EscapeTable{
+	Simple: map[rune]rune{
+		'a': '\a', 'b': '\b', 'f': '\f', 'n': '\n', 'r': '\r', 't': '\t', 'v': '\v',
+		'\\': '\\', '"': '"', '\'': '\'',
+	},
+	Multi: map[rune]int{'x': 2, 'u': 4, 'U': 8},
+	Named: true,
+	Octal: true,
 }
 
 // The Error type represents an error specific to failure in the dequoting/splitting
@@ -35,6 +91,10 @@ var (
 	MismatchedQuote = Error("mismatched quote")
 	// IncompleteBackslash means that a backslash happened at the end of input.
 	IncompleteBackslash = Error("incomplete backslash sequence")
+	// InvalidEscape means Options.StrictEscapes rejected a backslash escape:
+	// an unrecognized escape character, or a \x/\u/\U sequence with fewer
+	// hex digits than required.
+	InvalidEscape = Error("invalid escape sequence")
 )
 
 type state int
@@ -42,18 +102,30 @@ type state int
 const (
 	normal = state(iota)
 	quoted
+	singleQuoted
 	backslash
 	hex
+	named
+	octal
+	comment
+	heredocIntro
+	heredocBody
 )
 
 var stateFuncs []stateFunc
 
 func init() {
 	stateFuncs = []stateFunc{
-		normal:    stateNormal,
-		quoted:    stateQuoted,
-		backslash: stateBackslash,
-		hex:       stateHex,
+		normal:       stateNormal,
+		quoted:       stateQuoted,
+		singleQuoted: stateSingleQuoted,
+		backslash:    stateBackslash,
+		hex:          stateHex,
+		named:        stateNamed,
+		octal:        stateOctal,
+		comment:      stateComment,
+		heredocIntro: stateHeredocIntro,
+		heredocBody:  stateHeredocBody,
 	}
 }
 
@@ -68,16 +140,29 @@ func isExactSpace(r rune) bool {
 type stateFunc func(q *quoter, c rune)
 
 type quoter struct {
-	buf         strings.Builder // a buffer of suitably-dequoted characters that are actually in strings
-	states      []state
-	currentFunc stateFunc
-	partial     bool // do we have a partial word
-	isspace     func(c rune) bool
-	backslash   stateFunc
-	parseHex    int   // number of hex characters we want
-	hexValue    rune  // used to hold the values of \x and so on by fancyBackslash
-	indexes     []int // indexes of the words
-	err         error
+	buf              strings.Builder // a buffer of suitably-dequoted characters that are actually in strings
+	states           []state
+	currentFunc      stateFunc
+	partial          bool // do we have a partial word
+	isspace          func(c rune) bool
+	backslash        stateFunc
+	singleQuotes     bool // is '...' a literal-string region?
+	escapes          *EscapeTable
+	strictEscapes    bool
+	variableWidthHex bool
+	parseHex         int             // number of digits we still want
+	digitsGot        int             // number of digits consumed so far, for hexMin/StrictEscapes checks
+	hexMin           int             // minimum digits required for the current \x/\u/\U to not be InvalidEscape
+	hexValue         rune            // used to hold the values of \x and so on by fancyBackslash
+	namedOpen        bool            // have we seen the '{' of a \N{...} escape yet?
+	namedBuf         strings.Builder // accumulates the name in a \N{...} escape
+	commentChar      rune            // non-zero to enable CommentChar handling
+	hereDoc          bool            // does <<TAG start a here-doc?
+	heredocConfirmed bool            // have we seen the second '<' of a "<<"?
+	heredocTag       strings.Builder // the TAG in "<<TAG"
+	heredocLine      strings.Builder // the current, not-yet-committed line of a here-doc body
+	indexes          []int           // indexes of the words
+	err              error
 }
 
 func (q *quoter) push(s state) {
@@ -88,6 +173,17 @@ func (q *quoter) push(s state) {
 	q.currentFunc = stateFuncs[s]
 }
 
+// replace swaps the current state for s without changing stack depth, for
+// transitions between sibling states (such as heredocIntro to heredocBody)
+// that aren't pushes or pops.
+func (q *quoter) replace(s state) {
+	if len(q.states) == 0 {
+		q.states = append(q.states, normal)
+	}
+	q.states[len(q.states)-1] = s
+	q.currentFunc = stateFuncs[s]
+}
+
 func (q *quoter) pop() {
 	if len(q.states) > 1 {
 		q.states = q.states[:len(q.states)-1]
@@ -118,50 +214,56 @@ func simpleBackslash(q *quoter, c rune) {
 	q.pop()
 }
 
-// fancyBackslash handles C-style backslash escapes for common characters and
-// allows hex encoding of characters.
+// fancyBackslash handles C-style backslash escapes for common characters,
+// hex/unicode encoding of characters, and \N{...} Unicode names, all driven
+// by q.escapes so callers can plug in their own table via Options.Escapes.
 func fancyBackslash(q *quoter, c rune) {
 	// No matter what, the backslash processing is done. Things
 	// which need further digits will then push themselves, but
 	// when they're done, we go to the parent state.
 	q.pop()
-	switch c {
-	case 'x':
-		q.hexValue = 0
+	table := q.escapes
+	if table == nil {
+		table = DefaultEscapeTable
+	}
+	switch {
+	case c == 'N' && table.Named:
+		q.namedOpen = false
+		q.namedBuf.Reset()
+		q.push(named)
+	case c >= '0' && c <= '7' && table.Octal:
+		// the leading digit is already in hand, so consume up to 2 more.
+		// hexMin only matters to finishTrailingState's StrictEscapes check,
+		// which applies to both hex and octal; reset it here in case a
+		// previous \x/\u/\U left a stale value behind, since octal itself
+		// has no minimum digit count to enforce.
+		q.hexValue = rune(c - '0')
+		q.digitsGot = 1
+		q.hexMin = 0
 		q.parseHex = 2
-		q.push(hex)
-	case 'u':
-		q.hexValue = 0
-		q.parseHex = 4
-		q.push(hex)
-	case 'U':
+		q.push(octal)
+	case table.Multi[c] != 0:
+		width := table.Multi[c]
+		q.hexMin = width
+		if q.variableWidthHex && (c == 'x' || c == 'u') {
+			q.hexMin = 1
+		}
 		q.hexValue = 0
-		q.parseHex = 8
+		q.digitsGot = 0
+		q.parseHex = width
 		q.push(hex)
-	// the following code was written roughly five minutes before someone mentioned
-	// strconv.UnquoteChar to me.
-	case 'a':
-		q.buf.WriteRune('\a')
-	case 'b':
-		q.buf.WriteRune('\b')
-	case 'f':
-		q.buf.WriteRune('\f')
-	case 'n':
-		q.buf.WriteRune('\n')
-	case 'r':
-		q.buf.WriteRune('\r')
-	case 't':
-		q.buf.WriteRune('\t')
-	case 'v':
-		q.buf.WriteRune('\v')
-	case '\\':
-		q.buf.WriteRune('\\')
-	case '"':
-		q.buf.WriteRune('"')
-	case '\'':
-		q.buf.WriteRune('\'')
 	default:
-		q.err = Error(fmt.Sprintf("invalid backslash escape character '%c'", c))
+		// the following code was written roughly five minutes before someone mentioned
+		// strconv.UnquoteChar to me.
+		if r, ok := table.Simple[c]; ok {
+			q.buf.WriteRune(r)
+			return
+		}
+		if q.strictEscapes {
+			q.err = InvalidEscape
+		} else {
+			q.err = Error(fmt.Sprintf("invalid backslash escape character '%c'", c))
+		}
 		// but write it anyway
 		q.buf.WriteRune(c)
 	}
@@ -179,12 +281,15 @@ var hexDigits = [128]int8{
 }
 
 // stateHex handles hexadecimal inputs, accepting up to q.parseHex
-// digits (used to handle \x, \u, and \U with 2/4/8).
+// digits (used to handle \x, \u, and \U with 2/4/8). Fewer digits are
+// accepted too, unless StrictEscapes is set and the count is below q.hexMin,
+// which Options.VariableWidthHex can lower below the full width.
 func stateHex(q *quoter, c rune) {
 	if c < 128 {
 		val := hexDigits[c]
 		if val != -1 {
 			q.hexValue = q.hexValue*16 + rune(val)
+			q.digitsGot++
 			q.parseHex--
 			if q.parseHex == 0 {
 				q.buf.WriteRune(q.hexValue)
@@ -195,14 +300,72 @@ func stateHex(q *quoter, c rune) {
 			return
 		}
 	}
-	// write whatever hex value we got, even if we didn't get one, in which
-	// case it's zero
-	q.buf.WriteRune(q.hexValue)
+	// fewer digits than q.hexMin: either a hard error (StrictEscapes) or,
+	// as before, silently write whatever hex value we got, even if we
+	// didn't get one, in which case it's zero.
+	if q.digitsGot < q.hexMin && q.strictEscapes {
+		q.err = InvalidEscape
+	} else {
+		q.buf.WriteRune(q.hexValue)
+	}
 	q.pop()
 	// and hand the character we couldn't handle back to the previous state func
 	q.currentFunc(q, c)
 }
 
+// stateOctal handles \0-\377 octal escapes: up to 2 further digits after
+// the leading one already folded into q.hexValue by fancyBackslash, ending
+// at 3 digits, the first non-octal-digit character, or a digit that would
+// push the value past 0377 (255), the documented top of the octal range.
+func stateOctal(q *quoter, c rune) {
+	if c >= '0' && c <= '7' {
+		if v := q.hexValue*8 + rune(c-'0'); v <= 0377 {
+			q.hexValue = v
+			q.digitsGot++
+			q.parseHex--
+			if q.parseHex == 0 {
+				q.buf.WriteRune(q.hexValue)
+				q.pop()
+				return
+			}
+			return
+		}
+	}
+	q.buf.WriteRune(q.hexValue)
+	q.pop()
+	q.currentFunc(q, c)
+}
+
+// stateNamed handles \N{UNICODE NAME} escapes: the first call expects the
+// opening '{', then runes accumulate in q.namedBuf until the closing '}',
+// at which point the name is resolved against unicodeNames.
+func stateNamed(q *quoter, c rune) {
+	if !q.namedOpen {
+		if c != '{' {
+			q.err = Error(fmt.Sprintf("invalid \\N escape: expected '{', got %q", c))
+			q.pop()
+			q.currentFunc(q, c)
+			return
+		}
+		q.namedOpen = true
+		return
+	}
+	if c == '}' {
+		name := q.namedBuf.String()
+		q.namedBuf.Reset()
+		q.namedOpen = false
+		q.pop()
+		r, ok := unicodeNames[name]
+		if !ok {
+			q.err = Error(fmt.Sprintf("unknown unicode character name %q", name))
+			r = unicode.ReplacementChar
+		}
+		q.buf.WriteRune(r)
+		return
+	}
+	q.namedBuf.WriteRune(c)
+}
+
 func stateNormal(q *quoter, c rune) {
 	switch {
 	case q.isspace(c):
@@ -212,6 +375,15 @@ func stateNormal(q *quoter, c rune) {
 		q.push(backslash)
 	case c == '"':
 		q.push(quoted)
+	case c == '\'' && q.singleQuotes:
+		q.push(singleQuoted)
+	case c == q.commentChar && q.commentChar != 0:
+		q.newWord()
+		q.push(comment)
+		return
+	case c == '<' && q.hereDoc:
+		q.heredocConfirmed = false
+		q.push(heredocIntro)
 	default:
 		q.buf.WriteRune(c)
 	}
@@ -235,6 +407,106 @@ func stateQuoted(q *quoter, c rune) {
 	}
 }
 
+// stateSingleQuoted handles '...' literal regions: nothing is special except
+// the matching close quote, so there's no backslash handling and no nested
+// quote characters of any kind.
+func stateSingleQuoted(q *quoter, c rune) {
+	if c == '\'' {
+		q.pop()
+		return
+	}
+	q.buf.WriteRune(c)
+}
+
+// stateComment discards characters up to, but not including, the next '\n',
+// then hands that '\n' back to whatever state comment interrupted, the same
+// way space already terminates a word there.
+func stateComment(q *quoter, c rune) {
+	if c != '\n' {
+		return
+	}
+	q.pop()
+	q.currentFunc(q, c)
+}
+
+// stateHeredocIntro reads the "<TAG\n" that follows the '<' already consumed
+// by stateNormal: the first call confirms the second '<' of "<<", and
+// subsequent calls accumulate TAG until the '\n' that ends the intro line,
+// at which point it hands off to stateHeredocBody.
+func stateHeredocIntro(q *quoter, c rune) {
+	if !q.heredocConfirmed {
+		if c != '<' {
+			// not actually "<<"; the first '<' was just a literal character.
+			q.pop()
+			q.buf.WriteRune('<')
+			q.currentFunc(q, c)
+			return
+		}
+		q.heredocConfirmed = true
+		q.heredocTag.Reset()
+		return
+	}
+	if c == '\n' {
+		q.heredocLine.Reset()
+		q.replace(heredocBody)
+		return
+	}
+	q.heredocTag.WriteRune(c)
+}
+
+// finishTrailingState reconciles whatever state is on top of the stack at
+// the end of input. An open quote, or a heredoc whose closing TAG line
+// never arrived, becomes MismatchedQuote (after recovering whatever partial
+// content was captured); an open backslash sequence becomes
+// IncompleteBackslash. An unconfirmed "<" that never got its second "<" is
+// just a literal character, the same resolution stateHeredocIntro would
+// have made had any further input arrived. A hex or octal escape left
+// hanging at EOF gets the same treatment stateHex/stateOctal give a
+// non-digit terminator mid-string: InvalidEscape under StrictEscapes if it
+// came up short, otherwise whatever value was accumulated so far.
+func (q *quoter) finishTrailingState() {
+	switch q.states[len(q.states)-1] {
+	case quoted, singleQuoted:
+		q.err = MismatchedQuote
+	case heredocIntro:
+		if q.heredocConfirmed {
+			q.err = MismatchedQuote
+		} else {
+			q.buf.WriteRune('<')
+		}
+	case heredocBody:
+		q.buf.WriteString(q.heredocLine.String())
+		q.err = MismatchedQuote
+	case backslash, named:
+		q.buf.WriteRune('\\')
+		q.err = IncompleteBackslash
+	case hex, octal:
+		if q.digitsGot < q.hexMin && q.strictEscapes {
+			q.err = InvalidEscape
+		} else {
+			q.buf.WriteRune(q.hexValue)
+		}
+	}
+}
+
+// stateHeredocBody accumulates the here-doc's body a line at a time in
+// q.heredocLine; when a line exactly matches q.heredocTag, the body (not
+// including that line) becomes the word's value.
+func stateHeredocBody(q *quoter, c rune) {
+	if c != '\n' {
+		q.heredocLine.WriteRune(c)
+		return
+	}
+	if q.heredocLine.String() == q.heredocTag.String() {
+		q.pop()
+		q.newWord()
+		return
+	}
+	q.buf.WriteString(q.heredocLine.String())
+	q.buf.WriteRune('\n')
+	q.heredocLine.Reset()
+}
+
 // Split splits the given string into words, with behavior controlled
 // by the provided Options. If `q` is nil, it's treated like a zero
 // valued Options.
@@ -261,18 +533,17 @@ func Split(s string, qopt *Options) (results []string, err error) {
 	} else {
 		q.backslash = simpleBackslash
 	}
+	q.singleQuotes = opt.SingleQuotes
+	q.escapes = opt.Escapes
+	q.strictEscapes = opt.StrictEscapes
+	q.variableWidthHex = opt.VariableWidthHex
+	q.commentChar = opt.CommentChar
+	q.hereDoc = opt.HereDoc
 
 	for _, c := range s {
 		q.next(c)
 	}
-	switch q.states[len(q.states)-1] {
-	case quoted:
-		q.err = MismatchedQuote
-	case backslash:
-		q.buf.WriteRune('\\')
-		q.err = IncompleteBackslash
-	case hex:
-	}
+	q.finishTrailingState()
 
 	q.newWord()
 	bufStr := q.buf.String()
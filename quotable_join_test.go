@@ -0,0 +1,97 @@
+package quotable
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+// TestJoinRoundTrip checks that Split(Join(...)) reproduces the words from
+// the existing tests table, for every test whose input doesn't already
+// provoke an error (since those words aren't guaranteed to be a clean
+// round-trippable set to begin with).
+func TestJoinRoundTrip(t *testing.T) {
+	for _, q := range tests {
+		if q.err != "" {
+			continue
+		}
+		joined := Join(q.output, q.opts)
+		got, err := Split(joined, q.opts)
+		if err != nil {
+			t.Errorf("%s: Split(Join(%q)) failed: %s", q.name, q.output, err)
+			continue
+		}
+		if len(got) != len(q.output) {
+			t.Errorf("%s: Split(Join(%q)) = %q, length mismatch", q.name, q.output, got)
+			continue
+		}
+		for i, w := range q.output {
+			if got[i] != w {
+				t.Errorf("%s: Split(Join(%q))[%d] = %q, expected %q", q.name, q.output, i, got[i], w)
+			}
+		}
+	}
+}
+
+var joinTests = []quoteTest{
+	{name: "join_spaces", output: []string{"a b", "c"}},
+	{name: "join_quotes", output: []string{`a"b`, "c"}},
+	{name: "join_backslash", output: []string{`a\b`, "c"}},
+	{name: "join_empty", output: []string{"", "a"}},
+	{name: "join_fancy_control", opts: &Options{FancyBackslash: true}, output: []string{"a\tb\nc"}},
+	{name: "join_single_quotes_apostrophe", opts: &Options{SingleQuotes: true}, output: []string{"it's", "fine"}},
+	{name: "join_comment_char", opts: &Options{CommentChar: '#'}, output: []string{"#foo", "a#b", "c"}},
+	{name: "join_heredoc_marker", opts: &Options{HereDoc: true}, output: []string{"<<EOF", "a<<b", "c"}},
+}
+
+// FuzzJoin checks the same round-trip property as TestJoinRoundTrip
+// (Split(Join(words)) reproduces words) against arbitrary generated words,
+// with every quoting-relevant option turned on at once so any character
+// Quote fails to protect against (as CommentChar and HereDoc once were)
+// shows up as a fuzzing failure rather than requiring a hand-written case.
+func FuzzJoin(f *testing.F) {
+	for _, q := range tests {
+		for _, w := range q.output {
+			f.Add(w, w)
+		}
+	}
+	opts := &Options{FancyBackslash: true, SingleQuotes: true, CommentChar: '#', HereDoc: true}
+	f.Fuzz(func(t *testing.T, a, b string) {
+		if !utf8.ValidString(a) || !utf8.ValidString(b) {
+			t.Skip("not valid UTF-8; rune round-tripping isn't meaningful")
+		}
+		words := []string{a, b}
+		joined := Join(words, opts)
+		got, err := Split(joined, opts)
+		if err != nil {
+			t.Fatalf("Split(Join(%q)) failed: %s", words, err)
+		}
+		if len(got) != len(words) {
+			t.Fatalf("Split(Join(%q)) = %q, length mismatch", words, got)
+		}
+		for i := range words {
+			if got[i] != words[i] {
+				t.Fatalf("Split(Join(%q))[%d] = %q, want %q", words, i, got[i], words[i])
+			}
+		}
+	})
+}
+
+func TestJoinAndQuote(t *testing.T) {
+	for _, q := range joinTests {
+		joined := Join(q.output, q.opts)
+		got, err := Split(joined, q.opts)
+		if err != nil {
+			t.Errorf("%s: Split(Join(%q)) failed: %s", q.name, q.output, err)
+			continue
+		}
+		if len(got) != len(q.output) {
+			t.Errorf("%s: Split(Join(%q)) = %q, length mismatch", q.name, q.output, got)
+			continue
+		}
+		for i, w := range q.output {
+			if got[i] != w {
+				t.Errorf("%s: Split(Join(%q))[%d] = %q, expected %q", q.name, q.output, i, got[i], w)
+			}
+		}
+	}
+}
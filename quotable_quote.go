@@ -0,0 +1,112 @@
+package quotable
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// escapeNames maps runes to the short backslash escape used by
+// fancyBackslash, for use when producing output meant to be re-split with
+// FancyBackslash set. Keep this in sync with DefaultEscapeTable.Simple.
+var escapeNames = map[rune]byte{
+	'\a': 'a',
+	'\b': 'b',
+	'\f': 'f',
+	'\n': 'n',
+	'\r': 'r',
+	'\t': 't',
+	'\v': 'v',
+}
+
+// writeFancyEscape writes r as a backslash escape suitable for fancyBackslash
+// to read back: one of the short forms above, or a \x/\u/\U hex escape sized
+// to the rune's magnitude.
+func writeFancyEscape(buf *strings.Builder, r rune) {
+	if b, ok := escapeNames[r]; ok {
+		buf.WriteByte('\\')
+		buf.WriteByte(b)
+		return
+	}
+	switch {
+	case r <= 0xFF:
+		fmt.Fprintf(buf, `\x%02x`, r)
+	case r <= 0xFFFF:
+		fmt.Fprintf(buf, `\u%04x`, r)
+	default:
+		fmt.Fprintf(buf, `\U%08x`, r)
+	}
+}
+
+// Quote returns word rewritten so that Split(Quote(word, opts), opts)
+// reproduces word exactly. Backslashes and double quotes are always
+// backslash-escaped, as is ' when opts.SingleQuotes is set and the word is
+// not wrapped. The word is wrapped in double quotes only if it is empty or
+// contains whitespace or a double quote. A word containing opts.CommentChar,
+// or "<<" when opts.HereDoc is set, is also wrapped in double quotes, since
+// either would otherwise be read back as the start of a comment or a
+// here-doc rather than literal characters. A word needing none of this is
+// returned unchanged. If
+// opts.FancyBackslash is set, non-printable runes are emitted as \x, \u, \U,
+// or one of the short escapes (\n, \t, and so on) rather than literally.
+func Quote(word string, opts *Options) string {
+	var o Options
+	if opts != nil {
+		o = *opts
+	}
+	isspace := unicode.IsSpace
+	if o.OnlySpaceIsSpace {
+		isspace = isExactSpace
+	}
+	needsQuotes := word == ""
+	if o.CommentChar != 0 && strings.ContainsRune(word, o.CommentChar) {
+		// an unquoted, unescaped CommentChar anywhere in the word would
+		// start a comment when re-split, eating the rest of the word (and
+		// beyond, through the next '\n').
+		needsQuotes = true
+	}
+	if o.HereDoc && strings.Contains(word, "<<") {
+		// likewise, "<<" anywhere unquoted would be read back as the start
+		// of a here-doc rather than two literal '<' characters.
+		needsQuotes = true
+	}
+	for _, r := range word {
+		if isspace(r) || r == '"' {
+			needsQuotes = true
+			break
+		}
+	}
+	var buf strings.Builder
+	if needsQuotes {
+		buf.WriteByte('"')
+	}
+	for _, r := range word {
+		switch {
+		case r == '\\' || r == '"':
+			buf.WriteByte('\\')
+			buf.WriteRune(r)
+		case r == '\'' && o.SingleQuotes && !needsQuotes:
+			buf.WriteByte('\\')
+			buf.WriteRune(r)
+		case o.FancyBackslash && !unicode.IsPrint(r):
+			writeFancyEscape(&buf, r)
+		default:
+			buf.WriteRune(r)
+		}
+	}
+	if needsQuotes {
+		buf.WriteByte('"')
+	}
+	return buf.String()
+}
+
+// Join quotes each of words as needed and joins them with spaces, producing
+// a string which, when passed through Split with the same Options, returns
+// words unchanged. See Quote for the quoting rules applied to each word.
+func Join(words []string, opts *Options) string {
+	quoted := make([]string, len(words))
+	for i, w := range words {
+		quoted[i] = Quote(w, opts)
+	}
+	return strings.Join(quoted, " ")
+}
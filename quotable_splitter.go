@@ -0,0 +1,241 @@
+package quotable
+
+import (
+	"io"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ErrTooLong is reported by Splitter when a single token grows past the
+// size set by Buffer, mirroring bufio.Scanner's ErrTooLong.
+var ErrTooLong = Error("token too long")
+
+// defaultSplitterBuffer is the default maximum size, in bytes, of a single
+// token's buffered content before Splitter reports ErrTooLong.
+const defaultSplitterBuffer = 4096
+
+// Splitter tokenizes a stream of characters into words the same way Split
+// does, but incrementally, reusing the same state machine. It's meant for
+// input that shouldn't be read into memory all at once, such as a REPL or a
+// tailed log: a Splitter can be backed by an io.Reader, or fed directly via
+// Write or WriteRune, and tokens are retrieved one at a time with Next.
+type Splitter struct {
+	r        io.Reader
+	q        quoter
+	pending  []string
+	consumed int // bytes of q.buf already turned into pending tokens
+	max      int
+	leftover []byte // incomplete utf8 bytes held between Write calls
+	err      error
+	eof      bool
+}
+
+// NewSplitter creates a Splitter that reads from r, with behavior
+// controlled by opts exactly as for Split. r may be nil, in which case
+// input must be fed with Write or WriteRune, and Close used to signal the
+// end of input.
+func NewSplitter(r io.Reader, opts *Options) *Splitter {
+	var o Options
+	if opts != nil {
+		o = *opts
+	}
+	s := &Splitter{r: r, max: defaultSplitterBuffer}
+	s.q.states = append(s.q.states, normal)
+	s.q.currentFunc = stateFuncs[normal]
+	if o.OnlySpaceIsSpace {
+		s.q.isspace = isExactSpace
+	} else {
+		s.q.isspace = unicode.IsSpace
+	}
+	if o.FancyBackslash {
+		s.q.backslash = fancyBackslash
+	} else {
+		s.q.backslash = simpleBackslash
+	}
+	s.q.singleQuotes = o.SingleQuotes
+	s.q.escapes = o.Escapes
+	s.q.strictEscapes = o.StrictEscapes
+	s.q.variableWidthHex = o.VariableWidthHex
+	s.q.commentChar = o.CommentChar
+	s.q.hereDoc = o.HereDoc
+	return s
+}
+
+// Buffer sets the maximum size, in bytes, that a single token may grow to
+// before Next reports ErrTooLong. The default is 4096.
+func (s *Splitter) Buffer(max int) {
+	s.max = max
+}
+
+// Err returns the first terminal error encountered, if any, same as
+// bufio.Scanner's Err: it's nil until a real error (as opposed to running
+// out of input) occurs, and sticky afterward.
+func (s *Splitter) Err() error {
+	return s.err
+}
+
+// drain moves any tokens completed since the last call into pending.
+func (s *Splitter) drain() {
+	if len(s.q.indexes) == 0 {
+		return
+	}
+	bufStr := s.q.buf.String()
+	for _, next := range s.q.indexes {
+		s.pending = append(s.pending, bufStr[s.consumed:next])
+		s.consumed = next
+	}
+	s.q.indexes = s.q.indexes[:0]
+}
+
+// checkSize reports ErrTooLong if the as-yet-unterminated token has grown
+// past the configured Buffer size. A here-doc's current body line lives in
+// q.heredocLine until its closing '\n', not yet in q.buf, so it's counted
+// too; otherwise a single long here-doc line would bypass the limit
+// entirely until its line finally ended.
+func (s *Splitter) checkSize() {
+	if s.err == nil && s.q.buf.Len()-s.consumed+s.q.heredocLine.Len() > s.max {
+		s.err = ErrTooLong
+	}
+}
+
+// compact discards bytes already turned into pending tokens, so q.buf
+// doesn't retain the entire history of a long-running stream. It only runs
+// at a safe point: no partial word in progress, and every byte q.buf holds
+// has already been accounted for by s.consumed (drain, which runs just
+// before this, always empties q.indexes, so there's nothing left to
+// reference by offset into the old buffer).
+func (s *Splitter) compact() {
+	if s.q.partial || s.consumed != s.q.buf.Len() {
+		return
+	}
+	s.q.buf.Reset()
+	s.consumed = 0
+}
+
+// WriteRune feeds a single rune into the tokenizer, as an alternative to
+// backing the Splitter with an io.Reader -- useful for sources, such as a
+// REPL, that produce characters one at a time.
+func (s *Splitter) WriteRune(r rune) (int, error) {
+	if s.err != nil {
+		return 0, s.err
+	}
+	s.q.next(r)
+	s.drain()
+	s.checkSize()
+	s.compact()
+	return utf8.RuneLen(r), s.err
+}
+
+// Write feeds bytes into the tokenizer, decoding UTF-8 runes and carrying
+// any incomplete trailing sequence over to the next call, as an alternative
+// to backing the Splitter with an io.Reader.
+func (s *Splitter) Write(p []byte) (int, error) {
+	if s.err != nil {
+		return 0, s.err
+	}
+	n := len(p)
+	if len(s.leftover) > 0 {
+		p = append(s.leftover, p...)
+		s.leftover = nil
+	}
+	for len(p) > 0 {
+		r, size := utf8.DecodeRune(p)
+		if r == utf8.RuneError && size <= 1 && !utf8.FullRune(p) {
+			// an incomplete rune at the end of this chunk; wait for more.
+			s.leftover = append(s.leftover, p...)
+			break
+		}
+		s.q.next(r)
+		p = p[size:]
+	}
+	s.drain()
+	s.checkSize()
+	s.compact()
+	return n, s.err
+}
+
+// finish applies the same end-of-input handling Split does: an unterminated
+// quote or backslash sequence becomes a final, explicit error rather than
+// being silently dropped.
+func (s *Splitter) finish() {
+	s.q.finishTrailingState()
+	s.q.newWord()
+	s.drain()
+	if s.err == nil {
+		s.err = s.q.err
+	}
+}
+
+// Close signals that no more input will be written, for Splitters fed via
+// Write or WriteRune rather than backed by an io.Reader; it's a no-op for
+// Splitters backed by a reader, since those reach the same point when the
+// reader returns io.EOF. Close flushes any trailing partial token, applying
+// the same MismatchedQuote/IncompleteBackslash checks Split applies at the
+// end of input; call Next to retrieve that final token.
+func (s *Splitter) Close() error {
+	if !s.eof && s.r == nil {
+		s.eof = true
+		s.finish()
+	}
+	if s.err == nil {
+		return nil
+	}
+	return s.err
+}
+
+// Next returns the next complete token. If the Splitter is backed by an
+// io.Reader, Next reads from it as needed, a chunk at a time, until a token
+// is available. It returns io.EOF once the underlying reader is exhausted
+// and every complete or trailing-partial token has been returned; if the
+// trailing partial token was an unterminated quote or backslash sequence,
+// that token is returned together with MismatchedQuote or
+// IncompleteBackslash, the same way Split reports it. Once a terminal error
+// has been reported, later calls return that error again instead of
+// io.EOF. If the Splitter isn't
+// backed by a reader, Next returns io.EOF whenever no token is currently
+// available; call Close once input is finished to flush the final token
+// and any such error for it.
+func (s *Splitter) Next() (string, error) {
+	for {
+		if len(s.pending) > 0 {
+			tok := s.pending[0]
+			s.pending = s.pending[1:]
+			// if this was the last buffered token and a terminal error is
+			// waiting, report it alongside the token, the same way Split
+			// returns a final word together with its error.
+			if len(s.pending) == 0 && s.err != nil {
+				return tok, s.err
+			}
+			return tok, nil
+		}
+		if s.err != nil {
+			return "", s.err
+		}
+		if s.eof || s.r == nil {
+			return "", io.EOF
+		}
+		buf := make([]byte, 4096)
+		n, err := s.r.Read(buf)
+		if n > 0 {
+			// ignore the returned error: Write already drained any tokens
+			// completed before the failure into s.pending, and set s.err,
+			// which the top of the loop checks once those pending tokens
+			// are exhausted.
+			s.Write(buf[:n])
+		}
+		if err != nil {
+			s.eof = true
+			if err != io.EOF {
+				// don't return immediately: as with a Write error above, a
+				// Read can return n>0 together with a non-EOF error, and
+				// any token that completed in that same chunk is already
+				// sitting in s.pending. Setting s.err and looping back lets
+				// the pending check above drain it before this error is
+				// reported.
+				s.err = err
+				continue
+			}
+			s.finish()
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package quotable
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestSplitterReader(t *testing.T) {
+	s := NewSplitter(strings.NewReader(`a b c`), nil)
+	var got []string
+	for {
+		tok, err := s.Next()
+		if err != nil {
+			break
+		}
+		got = append(got, tok)
+	}
+	if err := s.Err(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := []string{"a", "b", "c"}
+	if len(got) != len(want) {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("token %d: got %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+// readErrWithData returns its fixed data together with a non-EOF error on
+// its one and only Read call, which io.Reader's contract permits: a caller
+// must process n>0 bytes even when err is also non-nil.
+type readErrWithData struct {
+	data []byte
+	err  error
+	done bool
+}
+
+func (r *readErrWithData) Read(p []byte) (int, error) {
+	if r.done {
+		return 0, r.err
+	}
+	r.done = true
+	n := copy(p, r.data)
+	return n, r.err
+}
+
+// A Reader is allowed by io.Reader's contract to return n>0 together with a
+// non-EOF error on its final call. A token that completed within that same
+// chunk must still reach Next, paired with the error, rather than being
+// discarded the moment the error is seen.
+func TestSplitterReaderErrorAfterCompleteToken(t *testing.T) {
+	readErr := Error("boom")
+	s := NewSplitter(&readErrWithData{data: []byte("ab cd"), err: readErr}, nil)
+	tok, err := s.Next()
+	if tok != "ab" {
+		t.Errorf("got token %q, want %q", tok, "ab")
+	}
+	if err != readErr {
+		t.Errorf("got err %v, want %v", err, readErr)
+	}
+	if _, err := s.Next(); err != readErr {
+		t.Errorf("Next after terminal error: got %v, want %v", err, readErr)
+	}
+}
+
+func TestSplitterMismatchedQuoteAtEOF(t *testing.T) {
+	s := NewSplitter(strings.NewReader(`"foo`), nil)
+	tok, err := s.Next()
+	if tok != "foo" {
+		t.Errorf("got token %q, want %q", tok, "foo")
+	}
+	if err != MismatchedQuote {
+		t.Errorf("got err %v, want %v", err, MismatchedQuote)
+	}
+	if _, err := s.Next(); err != MismatchedQuote {
+		t.Errorf("Next after terminal error: got %v, want %v", err, MismatchedQuote)
+	}
+}
+
+func TestSplitterWriteRune(t *testing.T) {
+	s := NewSplitter(nil, nil)
+	for _, r := range "a b" {
+		if _, err := s.WriteRune(r); err != nil {
+			t.Fatalf("WriteRune(%q): %s", r, err)
+		}
+	}
+	tok, err := s.Next()
+	if err != nil || tok != "a" {
+		t.Fatalf("got (%q, %v), want (%q, nil)", tok, err, "a")
+	}
+	if _, err := s.Next(); err != io.EOF {
+		t.Fatalf("got %v, want io.EOF (final token not yet flushed)", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close: %s", err)
+	}
+	tok, err = s.Next()
+	if err != nil || tok != "b" {
+		t.Fatalf("got (%q, %v), want (%q, nil)", tok, err, "b")
+	}
+}
+
+func TestSplitterBuffer(t *testing.T) {
+	s := NewSplitter(strings.NewReader(strings.Repeat("x", 100)), nil)
+	s.Buffer(10)
+	if _, err := s.Next(); err != ErrTooLong {
+		t.Fatalf("got %v, want %v", err, ErrTooLong)
+	}
+}
+
+// A single Read chunk can complete one token and then push the next past
+// the Buffer limit; Next must still hand back the completed token (paired
+// with ErrTooLong, the same way a final token is paired with a terminal
+// error elsewhere) instead of discarding it.
+func TestSplitterBufferTokenBeforeOverflow(t *testing.T) {
+	s := NewSplitter(strings.NewReader("ab "+strings.Repeat("x", 100)), nil)
+	s.Buffer(10)
+	tok, err := s.Next()
+	if tok != "ab" {
+		t.Errorf("got token %q, want %q", tok, "ab")
+	}
+	if err != ErrTooLong {
+		t.Errorf("got err %v, want %v", err, ErrTooLong)
+	}
+}
+
+// A Splitter tailing a long-running stream shouldn't retain every byte it's
+// ever seen: once a token is fully drained into pending and no partial word
+// remains, q.buf should be reset rather than growing for the life of the
+// Splitter.
+func TestSplitterCompactsBuffer(t *testing.T) {
+	s := NewSplitter(nil, nil)
+	for i := 0; i < 2000; i++ {
+		if _, err := s.Write([]byte("word ")); err != nil {
+			t.Fatalf("Write %d: %s", i, err)
+		}
+		tok, err := s.Next()
+		if err != nil || tok != "word" {
+			t.Fatalf("Next %d: got (%q, %v), want (%q, nil)", i, tok, err, "word")
+		}
+	}
+	if got := s.q.buf.Len(); got > 64 {
+		t.Errorf("q.buf.Len() = %d after 2000 drained tokens, want it compacted", got)
+	}
+}
+
+// A long here-doc line accumulates in q.heredocLine, not q.buf, until its
+// closing '\n'; checkSize needs to count it too, or Buffer's cap is
+// meaningless for here-docs.
+func TestSplitterBufferHereDoc(t *testing.T) {
+	s := NewSplitter(strings.NewReader("<<EOF\n"+strings.Repeat("x", 100)), &Options{HereDoc: true})
+	s.Buffer(10)
+	if _, err := s.Next(); err != ErrTooLong {
+		t.Fatalf("got %v, want %v", err, ErrTooLong)
+	}
+}
@@ -73,7 +73,47 @@ var tests = []quoteTest{
 	// to the backslash state, whoops.
 	{name: "backslash_x_x", opts: &Options{FancyBackslash: true}, input: `\x69x23`, output: []string{`ix23`}},
 	{name: "mismatched_quote", input: `"foo`, output: []string{`foo`}, err: "mismatched quote"},
+	{name: "single_quoted", opts: &Options{SingleQuotes: true}, input: `a'b c'd`, output: []string{"ab cd"}},
+	{name: "single_quoted_no_backslash", opts: &Options{SingleQuotes: true}, input: `'a\b'`, output: []string{`a\b`}},
+	{name: "single_quoted_ignores_double_quote", opts: &Options{SingleQuotes: true}, input: `'a"b'`, output: []string{`a"b`}},
+	{name: "single_quote_disabled_by_default", input: `'a b'`, output: []string{"'a", "b'"}},
+	{name: "mismatched_single_quote", opts: &Options{SingleQuotes: true}, input: `'foo`, output: []string{`foo`}, err: "mismatched quote"},
+	{name: "named_escape", opts: &Options{FancyBackslash: true}, input: `\N{BULLET}`, output: []string{"•"}},
+	{name: "named_escape_unknown", opts: &Options{FancyBackslash: true}, input: `\N{NOT A REAL NAME}`, output: []string{"�"}, err: `unknown unicode character name "NOT A REAL NAME"`},
+	{name: "named_escape_no_brace", opts: &Options{FancyBackslash: true}, input: `\Nx`, output: []string{"x"}, err: "invalid \\N escape: expected '{', got 'x'"},
+	{name: "octal", opts: &Options{FancyBackslash: true}, input: `\101\012`, output: []string{"A\n"}},
+	{name: "octal_three_digits_then_more", opts: &Options{FancyBackslash: true}, input: `\1019`, output: []string{"A9"}},
+	{name: "octal_overflow_bounded", opts: &Options{FancyBackslash: true}, input: `\477`, output: []string{"'7"}},
+	{name: "strict_escapes_short_hex", opts: &Options{FancyBackslash: true, StrictEscapes: true}, input: `\xza`, output: []string{"za"}, err: "invalid escape sequence"},
+	{name: "strict_escapes_unknown", opts: &Options{FancyBackslash: true, StrictEscapes: true}, input: `\q`, output: []string{"q"}, err: "invalid escape sequence"},
+	{name: "strict_escapes_short_hex_at_eof", opts: &Options{FancyBackslash: true, StrictEscapes: true}, input: `\x4`, output: []string{""}, err: "invalid escape sequence"},
+	{name: "fancy_hex_short_at_eof", opts: &Options{FancyBackslash: true}, input: `\x4`, output: []string{"\x04"}},
+	{name: "variable_width_hex", opts: &Options{FancyBackslash: true, VariableWidthHex: true}, input: `\x7z`, output: []string{"\x07z"}},
+	{name: "variable_width_hex_full_width", opts: &Options{FancyBackslash: true, VariableWidthHex: true}, input: `\x7a`, output: []string{"\x7a"}},
+	{name: "variable_width_u", opts: &Options{FancyBackslash: true, VariableWidthHex: true}, input: `\u6z`, output: []string{"\x06z"}},
 	{name: "long_data", input: `a b c d e f g h i j k l m n o p q r s t u v w x y z`, output: []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k", "l", "m", "n", "o", "p", "q", "r", "s", "t", "u", "v", "w", "x", "y", "z"}},
+	{name: "comment", opts: &Options{CommentChar: '#'}, input: "a b #c d\ne", output: []string{"a", "b", "e"}},
+	{name: "comment_at_eof_no_newline", opts: &Options{CommentChar: '#'}, input: "a #b c", output: []string{"a"}},
+	{name: "comment_char_disabled_by_default", input: "a #b", output: []string{"a", "#b"}},
+	{name: "heredoc", opts: &Options{HereDoc: true}, input: "a <<EOF\nline one\nline two\nEOF\nb", output: []string{"a", "line one\nline two\n", "b"}},
+	{name: "heredoc_empty_body", opts: &Options{HereDoc: true}, input: "<<EOF\nEOF\n", output: []string{""}},
+	{name: "heredoc_not_double_angle", opts: &Options{HereDoc: true}, input: "a <b", output: []string{"a", "<b"}},
+	{name: "heredoc_disabled_by_default", input: "a <<EOF\nb\nEOF\n", output: []string{"a", "<<EOF", "b", "EOF"}},
+	{name: "mismatched_heredoc", opts: &Options{HereDoc: true}, input: "<<EOF\nline one\n", output: []string{"line one\n"}, err: "mismatched quote"},
+	{name: "heredoc_lone_angle_at_eof", opts: &Options{HereDoc: true}, input: "a <", output: []string{"a", "<"}},
+}
+
+// customEscapeTableTests aren't in tests because Join/Quote don't consult
+// Options.Escapes, so a word needing a non-default escape wouldn't survive
+// the round trip TestJoinRoundTrip checks for every entry in tests.
+var customEscapeTableTests = []quoteTest{
+	{name: "custom_escape_table", opts: &Options{FancyBackslash: true, Escapes: &EscapeTable{Simple: map[rune]rune{'e': 0x1b}}}, input: `\e`, output: []string{"\x1b"}},
+}
+
+func TestCustomEscapeTable(t *testing.T) {
+	for _, q := range customEscapeTableTests {
+		doTest(q, t)
+	}
 }
 
 func TestBasicQuoting(t *testing.T) {
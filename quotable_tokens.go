@@ -0,0 +1,101 @@
+package quotable
+
+import (
+	"unicode"
+)
+
+// tokenPos records a position in the input a Token's start or end lines up
+// with, in the units SplitTokens reports.
+type tokenPos struct {
+	byteOffset, runeOffset, line, column int
+}
+
+// Token is a word produced by SplitTokens, together with the position in
+// the original input it came from. StartByte/EndByte and StartRune/EndRune
+// are 0-based, half-open ranges over the input string's bytes and runes
+// respectively; Line and Column are 1-based and point at the token's first
+// character, counting newlines the same way most parsers and editors do.
+type Token struct {
+	Value              string
+	StartByte, EndByte int
+	StartRune, EndRune int
+	Line, Column       int
+}
+
+// SplitTokens is like Split, but returns each word as a Token carrying the
+// position it was found at, so callers building a higher-level grammar on
+// top of quotable can report errors (or highlight source ranges) pointing
+// at the exact place a token came from.
+func SplitTokens(s string, qopt *Options) ([]Token, error) {
+	var opt Options
+	if qopt != nil {
+		opt = *qopt
+	}
+	var q quoter
+	q.states = append(q.states, normal)
+	q.currentFunc = stateFuncs[normal]
+	if opt.OnlySpaceIsSpace {
+		q.isspace = isExactSpace
+	} else {
+		q.isspace = unicode.IsSpace
+	}
+	if opt.FancyBackslash {
+		q.backslash = fancyBackslash
+	} else {
+		q.backslash = simpleBackslash
+	}
+	q.singleQuotes = opt.SingleQuotes
+	q.escapes = opt.Escapes
+	q.strictEscapes = opt.StrictEscapes
+	q.variableWidthHex = opt.VariableWidthHex
+	q.commentChar = opt.CommentChar
+	q.hereDoc = opt.HereDoc
+
+	var starts, ends []tokenPos
+	runeIdx, line, col := 0, 1, 1
+	for b, c := range s {
+		wasPartial := q.partial
+		here := tokenPos{byteOffset: b, runeOffset: runeIdx, line: line, column: col}
+		nIndexes := len(q.indexes)
+		q.next(c)
+		// a word starts exactly when partial flips false -> true, and ends
+		// exactly when next(c) appends to q.indexes; both happen at most
+		// once per character, so "here" is the right position for either.
+		if !wasPartial && q.partial {
+			starts = append(starts, here)
+		}
+		if len(q.indexes) > nIndexes {
+			ends = append(ends, here)
+		}
+		if c == '\n' {
+			line++
+			col = 1
+		} else {
+			col++
+		}
+		runeIdx++
+	}
+	q.finishTrailingState()
+	nIndexes := len(q.indexes)
+	q.newWord()
+	if len(q.indexes) > nIndexes {
+		ends = append(ends, tokenPos{byteOffset: len(s), runeOffset: runeIdx, line: line, column: col})
+	}
+
+	bufStr := q.buf.String()
+	tokens := make([]Token, len(q.indexes))
+	prev := 0
+	for i, next := range q.indexes {
+		tokens[i] = Token{
+			Value:     bufStr[prev:next],
+			StartByte: starts[i].byteOffset,
+			EndByte:   ends[i].byteOffset,
+			StartRune: starts[i].runeOffset,
+			EndRune:   ends[i].runeOffset,
+			Line:      starts[i].line,
+			Column:    starts[i].column,
+		}
+		prev = next
+	}
+	return tokens, q.err
+}
@@ -0,0 +1,58 @@
+package quotable
+
+import (
+	"testing"
+)
+
+func TestSplitTokens(t *testing.T) {
+	toks, err := SplitTokens("a bb\nccc ddd", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := []Token{
+		{Value: "a", StartByte: 0, EndByte: 1, StartRune: 0, EndRune: 1, Line: 1, Column: 1},
+		{Value: "bb", StartByte: 2, EndByte: 4, StartRune: 2, EndRune: 4, Line: 1, Column: 3},
+		{Value: "ccc", StartByte: 5, EndByte: 8, StartRune: 5, EndRune: 8, Line: 2, Column: 1},
+		{Value: "ddd", StartByte: 9, EndByte: 12, StartRune: 9, EndRune: 12, Line: 2, Column: 5},
+	}
+	if len(toks) != len(want) {
+		t.Fatalf("got %d tokens, want %d: %+v", len(toks), len(want), toks)
+	}
+	for i, w := range want {
+		if toks[i] != w {
+			t.Errorf("token %d: got %+v, want %+v", i, toks[i], w)
+		}
+	}
+}
+
+func TestSplitTokensQuotedAndMismatched(t *testing.T) {
+	toks, err := SplitTokens(`"foo`, nil)
+	if err != MismatchedQuote {
+		t.Fatalf("got err %v, want %v", err, MismatchedQuote)
+	}
+	if len(toks) != 1 || toks[0].Value != "foo" {
+		t.Fatalf("got %+v, want single token %q", toks, "foo")
+	}
+	if toks[0].StartByte != 0 || toks[0].EndByte != 4 {
+		t.Errorf("got byte range [%d,%d), want [0,4)", toks[0].StartByte, toks[0].EndByte)
+	}
+}
+
+func TestSplitTokensValuesMatchSplit(t *testing.T) {
+	for _, q := range tests {
+		toks, tErr := SplitTokens(q.input, q.opts)
+		words, wErr := Split(q.input, q.opts)
+		if tErr != wErr {
+			t.Errorf("%s: SplitTokens err %v != Split err %v", q.name, tErr, wErr)
+		}
+		if len(toks) != len(words) {
+			t.Errorf("%s: SplitTokens returned %d tokens, Split returned %d words", q.name, len(toks), len(words))
+			continue
+		}
+		for i, w := range words {
+			if toks[i].Value != w {
+				t.Errorf("%s: token %d value %q != Split word %q", q.name, i, toks[i].Value, w)
+			}
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package quotable
+
+// unicodeNames is a small, hand-curated table of Unicode character names
+// for \N{...} escapes, covering the ASCII control/punctuation names and a
+// handful of common symbols. It's nowhere near the full UnicodeData name
+// table, and an EscapeTable cannot supply other names: Options.Escapes.Named
+// only controls whether fancyBackslash recognizes the \N{...} form at all.
+var unicodeNames = map[string]rune{
+	"NULL":                        0x0000,
+	"BELL":                        0x0007,
+	"BACKSPACE":                   0x0008,
+	"HORIZONTAL TABULATION":       0x0009,
+	"LINE FEED":                   0x000A,
+	"VERTICAL TABULATION":         0x000B,
+	"FORM FEED":                   0x000C,
+	"CARRIAGE RETURN":             0x000D,
+	"ESCAPE":                      0x001B,
+	"SPACE":                       0x0020,
+	"EXCLAMATION MARK":            0x0021,
+	"QUOTATION MARK":              0x0022,
+	"NUMBER SIGN":                 0x0023,
+	"DOLLAR SIGN":                 0x0024,
+	"PERCENT SIGN":                0x0025,
+	"AMPERSAND":                   0x0026,
+	"APOSTROPHE":                  0x0027,
+	"ASTERISK":                    0x002A,
+	"PLUS SIGN":                   0x002B,
+	"COMMA":                       0x002C,
+	"HYPHEN-MINUS":                0x002D,
+	"FULL STOP":                   0x002E,
+	"SOLIDUS":                     0x002F,
+	"COLON":                       0x003A,
+	"SEMICOLON":                   0x003B,
+	"LESS-THAN SIGN":              0x003C,
+	"EQUALS SIGN":                 0x003D,
+	"GREATER-THAN SIGN":           0x003E,
+	"QUESTION MARK":               0x003F,
+	"COMMERCIAL AT":               0x0040,
+	"LEFT SQUARE BRACKET":         0x005B,
+	"REVERSE SOLIDUS":             0x005C,
+	"RIGHT SQUARE BRACKET":        0x005D,
+	"CIRCUMFLEX ACCENT":           0x005E,
+	"LOW LINE":                    0x005F,
+	"GRAVE ACCENT":                0x0060,
+	"LEFT CURLY BRACKET":          0x007B,
+	"VERTICAL LINE":               0x007C,
+	"RIGHT CURLY BRACKET":         0x007D,
+	"TILDE":                       0x007E,
+	"NO-BREAK SPACE":              0x00A0,
+	"DEGREE SIGN":                 0x00B0,
+	"EURO SIGN":                   0x20AC,
+	"EN DASH":                     0x2013,
+	"EM DASH":                     0x2014,
+	"BULLET":                      0x2022,
+	"HORIZONTAL ELLIPSIS":         0x2026,
+	"LEFT SINGLE QUOTATION MARK":  0x2018,
+	"RIGHT SINGLE QUOTATION MARK": 0x2019,
+	"LEFT DOUBLE QUOTATION MARK":  0x201C,
+	"RIGHT DOUBLE QUOTATION MARK": 0x201D,
+	"WHITE SMILING FACE":          0x263A,
+	"BLACK STAR":                  0x2605,
+	"CHECK MARK":                  0x2713,
+	"SNOWMAN":                     0x2603,
+}